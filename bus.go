@@ -0,0 +1,66 @@
+package ina226
+
+import "github.com/saljam/i2c"
+
+// Bus is the hardware-abstraction layer Ina226 talks to. It models the
+// register read/write protocol shared by TI's current-monitor chips (the
+// INA226 and INA219), decoupling the driver from any particular I2C
+// implementation and making it possible to test against an in-memory fake.
+type Bus interface {
+	WriteRegister(reg byte, w uint16) error
+	ReadRegister(reg byte) (uint16, error)
+}
+
+// i2cBus adapts github.com/saljam/i2c.Device to the Bus interface.
+type i2cBus struct {
+	device *i2c.Device
+}
+
+// NewI2CBus wraps an already-opened i2c.Device as a Bus.
+func NewI2CBus(device *i2c.Device) Bus {
+	return &i2cBus{device: device}
+}
+
+func (b *i2cBus) WriteRegister(reg byte, w uint16) error {
+	buf := append([]byte{reg}, wordToByteArray(w)...)
+	_, err := b.device.Write(buf)
+	return err
+}
+
+func (b *i2cBus) ReadRegister(reg byte) (uint16, error) {
+	// send request to register
+	_, err := b.device.Write([]byte{reg})
+	if err != nil {
+		return 0, err
+	}
+	// read the 16 bit register content
+	buf := make([]byte, 2)
+	_, err = b.device.Read(buf)
+	if err != nil {
+		return 0, err
+	}
+	return uint16(buf[0])<<8 | uint16(buf[1]), nil
+}
+
+// FakeBus is an in-memory Bus implementation backed by a register map, with
+// no real I2C traffic. It's meant for unit-testing code built on top of Bus,
+// including Ina226 itself: pre-seed Registers to simulate a device state,
+// or inspect it after exercising the driver to assert what was written.
+type FakeBus struct {
+	Registers map[byte]uint16
+}
+
+// NewFakeBus returns an empty FakeBus, reading as zero from every register
+// until written to or seeded via Registers.
+func NewFakeBus() *FakeBus {
+	return &FakeBus{Registers: make(map[byte]uint16)}
+}
+
+func (b *FakeBus) WriteRegister(reg byte, w uint16) error {
+	b.Registers[reg] = w
+	return nil
+}
+
+func (b *FakeBus) ReadRegister(reg byte) (uint16, error) {
+	return b.Registers[reg], nil
+}
@@ -0,0 +1,210 @@
+package ina219
+
+// INA219 library
+// Datasheet: http://www.ti.com/lit/ds/symlink/ina219.pdf
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Fede85/go-ina226"
+	"github.com/saljam/i2c"
+)
+
+// Registers address map
+const (
+	CONFIG_REG       byte = 0x00
+	SHUNTVOLTAGE_REG byte = 0x01
+	BUSVOLTAGE_REG   byte = 0x02
+	POWER_REG        byte = 0x03
+	CURRENT_REG      byte = 0x04
+	CALIBRATION_REG  byte = 0x05
+)
+
+// Configuration register helper constants
+//
+//   15   14 13    12  11    10  9  8  7  6  5  4  3    2    1    0
+//  _____ ______ ______ ______ ______ _____ _____ _____
+// |     |      |      |      |      |     |     |     |
+// | RST |  -   | BRNG | PG1 PG0 | BADC3..0 | SADC3..0 | MODE2..0 |
+// |_____|______|______|______|______|_____|_____|_____|
+
+const (
+	// RST bit
+	INA219_RST uint16 = 0x01 << 15
+
+	// BRNG: bus voltage range
+	INA219_BRNG_16V uint16 = 0x00 << 13
+	INA219_BRNG_32V uint16 = 0x01 << 13
+
+	// PGA: shunt voltage range (gain)
+	INA219_PGA_40MV  uint16 = 0x00 << 11
+	INA219_PGA_80MV  uint16 = 0x01 << 11
+	INA219_PGA_160MV uint16 = 0x02 << 11
+	INA219_PGA_320MV uint16 = 0x03 << 11
+
+	// BADC: bus ADC resolution/averaging (4-bit)
+	INA219_BADC_9BIT    uint16 = 0x00 << 7
+	INA219_BADC_10BIT   uint16 = 0x01 << 7
+	INA219_BADC_11BIT   uint16 = 0x02 << 7
+	INA219_BADC_12BIT   uint16 = 0x03 << 7
+	INA219_BADC_2SAMP   uint16 = 0x09 << 7
+	INA219_BADC_4SAMP   uint16 = 0x0A << 7
+	INA219_BADC_8SAMP   uint16 = 0x0B << 7
+	INA219_BADC_16SAMP  uint16 = 0x0C << 7
+	INA219_BADC_32SAMP  uint16 = 0x0D << 7
+	INA219_BADC_64SAMP  uint16 = 0x0E << 7
+	INA219_BADC_128SAMP uint16 = 0x0F << 7
+
+	// SADC: shunt ADC resolution/averaging (4-bit)
+	INA219_SADC_9BIT    uint16 = 0x00 << 3
+	INA219_SADC_10BIT   uint16 = 0x01 << 3
+	INA219_SADC_11BIT   uint16 = 0x02 << 3
+	INA219_SADC_12BIT   uint16 = 0x03 << 3
+	INA219_SADC_2SAMP   uint16 = 0x09 << 3
+	INA219_SADC_4SAMP   uint16 = 0x0A << 3
+	INA219_SADC_8SAMP   uint16 = 0x0B << 3
+	INA219_SADC_16SAMP  uint16 = 0x0C << 3
+	INA219_SADC_32SAMP  uint16 = 0x0D << 3
+	INA219_SADC_64SAMP  uint16 = 0x0E << 3
+	INA219_SADC_128SAMP uint16 = 0x0F << 3
+
+	// MODE: operating mode (3-bit)
+	INA219_MODE_POWER_DOWN     uint16 = 0x00
+	INA219_MODE_SHUNT_TRIG     uint16 = 0x01
+	INA219_MODE_BUS_TRIG       uint16 = 0x02
+	INA219_MODE_SHUNT_BUS_TRIG uint16 = 0x03
+	INA219_MODE_ADC_OFF        uint16 = 0x04
+	INA219_MODE_SHUNT_CONT     uint16 = 0x05
+	INA219_MODE_BUS_CONT       uint16 = 0x06
+	INA219_MODE_SHUNT_BUS_CONT uint16 = 0x07
+)
+
+// BUS_VOLTAGE register helper bits: the 13-bit bus voltage reading shares the
+// register with a math-overflow flag and a conversion-ready flag, so unlike
+// INA226's it must be shifted right by busVoltageShift before scaling.
+const (
+	INA219_CNVR uint16 = 0x01 << 1
+	INA219_OVF  uint16 = 0x01 << 0
+)
+
+const busVoltageShift = 3
+
+type Ina219 struct {
+	bus ina226.Bus
+
+	// calibration variables
+	rShunt, iMax, currentLSB float64
+}
+
+func New(bus int, addr byte) (*Ina219, error) {
+	dev, err := i2c.NewDevice(bus, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewWithBus(ina226.NewI2CBus(dev)), nil
+}
+
+// NewWithBus wraps an already-constructed ina226.Bus, bypassing i2c device
+// discovery. This is the entry point for running against an
+// ina226.FakeBus in tests, or any other Bus implementation.
+func NewWithBus(bus ina226.Bus) *Ina219 {
+	return &Ina219{bus: bus}
+}
+
+func (ina219 *Ina219) Configure(confs ...uint16) error {
+	var configuration uint16
+
+	for _, conf := range confs {
+		configuration |= conf
+	}
+
+	return ina219.bus.WriteRegister(CONFIG_REG, configuration)
+}
+
+func (ina219 *Ina219) Reset() error {
+	// reset bit ins in the configure register
+	return ina219.bus.WriteRegister(CONFIG_REG, INA219_RST)
+}
+
+// Calibrate programs the CALIBRATION register from the shunt resistor value
+// and the maximum current the application expects to measure, using the raw
+// current_LSB = iMax / 32768. Unlike go-ina226's Ina226.Calibrate, the
+// current_LSB is not rounded up with the mantissa-ceiling approximation.
+func (ina219 *Ina219) Calibrate(rShuntValue float64, iMaxValue float64) error {
+	if rShuntValue <= 0.0 {
+		return errors.New(fmt.Sprintf("rShunt value: %f is not correct. Must be greater than 0", rShuntValue))
+	}
+	if iMaxValue <= 0.0 {
+		return errors.New(fmt.Sprintf("iMax value: %f is not correct. Must be greater than 0", iMaxValue))
+	}
+
+	currentLSB := iMaxValue / 32768
+
+	calibrationValue := uint16(0.04096 / (currentLSB * rShuntValue))
+
+	err := ina219.bus.WriteRegister(CALIBRATION_REG, calibrationValue)
+	if err != nil {
+		return err
+	}
+
+	ina219.rShunt = rShuntValue
+	ina219.iMax = iMaxValue
+	ina219.currentLSB = currentLSB
+	return nil
+}
+
+func (ina219 *Ina219) ReadBusVoltage() (float64, error) {
+	reg, err := ina219.bus.ReadRegister(BUSVOLTAGE_REG)
+	if err != nil {
+		return 0, err
+	}
+	return float64(reg>>busVoltageShift) * 0.004, nil
+}
+
+// ReadBusVoltageFlags reads the BUS_VOLTAGE register and decodes its two
+// low-order status bits: overflow (OVF, the shunt ADC or the current/power
+// calculations exceeded the chosen PGA range, so ReadBusVoltage/ReadCurrent/
+// ReadPower are unreliable) and conversionReady (CNVR, a conversion has
+// completed; cleared by writing CONFIG_REG or reading POWER_REG).
+func (ina219 *Ina219) ReadBusVoltageFlags() (overflow, conversionReady bool, err error) {
+	reg, err := ina219.bus.ReadRegister(BUSVOLTAGE_REG)
+	if err != nil {
+		return false, false, err
+	}
+	return reg&INA219_OVF != 0, reg&INA219_CNVR != 0, nil
+}
+
+func (ina219 *Ina219) ReadShuntVoltage() (float64, error) {
+	reg, err := ina219.bus.ReadRegister(SHUNTVOLTAGE_REG)
+	if err != nil {
+		return 0, err
+	}
+	return float64(int16(reg)) * 0.00001, nil
+}
+
+func (ina219 *Ina219) ReadCurrent() (float64, error) {
+	if ina219.currentLSB <= 0.0 {
+		return 0, errors.New("device is not calibrated, call Calibrate first")
+	}
+
+	reg, err := ina219.bus.ReadRegister(CURRENT_REG)
+	if err != nil {
+		return 0, err
+	}
+	return float64(int16(reg)) * ina219.currentLSB, nil
+}
+
+// ReadPower reads the POWER register and returns the bus power in Watt,
+// computed as raw * Power_LSB where Power_LSB = 20 * Current_LSB.
+func (ina219 *Ina219) ReadPower() (float64, error) {
+	if ina219.currentLSB <= 0.0 {
+		return 0, errors.New("device is not calibrated, call Calibrate first")
+	}
+
+	reg, err := ina219.bus.ReadRegister(POWER_REG)
+	if err != nil {
+		return 0, err
+	}
+	return float64(reg) * 20 * ina219.currentLSB, nil
+}
@@ -94,11 +94,46 @@ const (
 	INA226_RST uint16 = 0x01 << 15
 )
 
+// MASK/ENABLE register helper constants
+//
+//   15    14    13    12    11    10    9    8    7    6    5    4     3     2     1    0
+//  _____ _____ _____ _____ _____ _____ ____ ____ ____ ____ ____ _____ _____ _____ ____ ____
+// |     |     |     |     |     |     |    |    |    |    |    |     |     |     |    |    |
+// | SOL | SUL | BOL | BUL | POL | CNVR|  -  |  -  |  -  |  -  |  -  | AFF | CVRF | OVF |APOL| LEN|
+// |_____|_____|_____|_____|_____|_____|____|____|____|____|____|_____|_____|_____|____|____|
+const (
+	// Alert function select: programs which comparison ALERT_LIMIT is used for.
+	// Pass exactly one of these to SetAlertFunction.
+	INA226_SHUNT_OVER_VOLTAGE  uint16 = 0x01 << 15
+	INA226_SHUNT_UNDER_VOLTAGE uint16 = 0x01 << 14
+	INA226_BUS_OVER_VOLTAGE    uint16 = 0x01 << 13
+	INA226_BUS_UNDER_VOLTAGE   uint16 = 0x01 << 12
+	INA226_POWER_OVER_LIMIT    uint16 = 0x01 << 11
+	INA226_CONVERSION_READY    uint16 = 0x01 << 10
+
+	// Status flags, read-only, returned decoded by ReadAlertFlags
+	INA226_ALERT_FUNCTION_FLAG   uint16 = 0x01 << 4
+	INA226_CONVERSION_READY_FLAG uint16 = 0x01 << 3
+	INA226_MATH_OVERFLOW_FLAG    uint16 = 0x01 << 2
+
+	// Alert pin behaviour, OR these in with the mode passed to SetAlertFunction
+	INA226_ALERT_POLARITY     uint16 = 0x01 << 1
+	INA226_ALERT_LATCH_ENABLE uint16 = 0x01 << 0
+)
+
+// alertFunctionMask covers the bits that select what ALERT_LIMIT is compared against.
+const alertFunctionMask uint16 = INA226_SHUNT_OVER_VOLTAGE | INA226_SHUNT_UNDER_VOLTAGE |
+	INA226_BUS_OVER_VOLTAGE | INA226_BUS_UNDER_VOLTAGE | INA226_POWER_OVER_LIMIT | INA226_CONVERSION_READY
+
 type Ina226 struct {
-	device *i2c.Device
+	bus Bus
 
 	// calibration variables
 	rShunt, iMax, vBusMax, vShuntMax float64
+	currentLSB                       float64
+
+	// alert subsystem: function currently selected via SetAlertFunction
+	alertMode uint16
 }
 
 func New(bus int, addr byte) (*Ina226, error) {
@@ -106,9 +141,15 @@ func New(bus int, addr byte) (*Ina226, error) {
 	if err != nil {
 		return nil, err
 	}
-	ina226 := Ina226{device: dev}
 
-	return &ina226, nil
+	return NewWithBus(NewI2CBus(dev)), nil
+}
+
+// NewWithBus wraps an already-constructed Bus, bypassing i2c device discovery.
+// This is the entry point for running against a FakeBus in tests, or any other
+// Bus implementation.
+func NewWithBus(bus Bus) *Ina226 {
+	return &Ina226{bus: bus}
 }
 
 func wordToByteArray(w uint16) []byte {
@@ -124,72 +165,107 @@ func (ina226 *Ina226) Configure(confs ...uint16) error {
 	for _, conf := range confs {
 		configuration |= conf
 	}
-	var buf []byte
-	buf = append(buf, CONFIG_REG)
-	buf = append(buf, wordToByteArray(configuration)...)
 
-	_, err := ina226.device.Write(buf)
-	if err != nil {
-		return err
+	return ina226.bus.WriteRegister(CONFIG_REG, configuration)
+}
+
+// ErrCalibrationOutOfRange is returned by Calibrate and SetMaxCurrentShunt when
+// the requested maxCurrent/shunt combination cannot be represented in the
+// CALIBRATION register: either the shunt voltage at maxCurrent would exceed the
+// ADC's ±81.92 mV range, or the resulting calibration value falls outside the
+// register's 1..0x7FFF range (bit 15 of CALIBRATION is reserved and must be 0).
+var ErrCalibrationOutOfRange = errors.New("calibration out of range: maxCurrent/shunt combination cannot be represented")
+
+// calibrationFor computes the current_LSB and CALIBRATION register value for a
+// given maxCurrent/shunt pair, without touching the device. With normalize=true
+// it applies the mantissa-ceiling approximation from the datasheet so that
+// current_LSB is a "round" number; with normalize=false it keeps the raw
+// current_LSB, matching the "fine-tune" mode of the Arduino library.
+func (ina226 *Ina226) calibrationFor(maxCurrent, shunt float64, normalize bool) (currentLSB float64, calibrationValue uint16, err error) {
+	if shunt <= 0.0 {
+		return 0, 0, errors.New(fmt.Sprintf("shunt value: %f is not correct. Must be greater than 0", shunt))
 	}
-	return nil
+	if maxCurrent <= 0.0 {
+		return 0, 0, errors.New(fmt.Sprintf("maxCurrent value: %f is not correct. Must be greater than 0", maxCurrent))
+	}
+	// the shunt voltage at maxCurrent must fit in the ADC's +-81.92 mV range
+	if maxCurrent*shunt > 0.08192 {
+		return 0, 0, ErrCalibrationOutOfRange
+	}
+
+	currentLSB = maxCurrent / 32768
+	if normalize {
+		currentLSB *= 1000000 // transform to micro Ampere
+		// As described in the datasheet to simplify calculation we should approximate the current LSB number
+		// the method used is following described:
+		// first extract from the currentLSB normalized notation only the mantissa
+		currentLSB_mantissa := currentLSB / (math.Pow(10, math.Floor(math.Log10(currentLSB))))
+		// then apply the ceiling function and multiply for the exponent
+		currentLSB_approx := math.Ceil(currentLSB_mantissa) * math.Pow(10, math.Floor((math.Log10(currentLSB))))
+		currentLSB = currentLSB_approx / 1000000 //transform back to Ampere
+	}
+
+	calibration := 0.00512 / (currentLSB * shunt)
+	if calibration < 1 || calibration > 0x7FFF {
+		return 0, 0, ErrCalibrationOutOfRange
+	}
+
+	return currentLSB, uint16(calibration), nil
 }
 
 func (ina226 *Ina226) Calibrate(rShuntValue float64, iMaxValue float64) error {
-	ina226.rShunt = rShuntValue
-	ina226.iMax = iMaxValue
-
-	currentLSB := ina226.iMax / 32768
-	currentLSB *= 1000000 // transform to micro Ampere
-	// As described in the datasheet to simplify calculation we should approximate the current LSB number
-	// the method used is following described:
-	// first extract from the currentLSB normalized notation only the mantissa
-	currentLSB_mantissa := currentLSB / (math.Pow(10, math.Floor(math.Log10(currentLSB))))
-	// then apply the ceiling function and multiply for the exponent
-	currentLSB_approx := math.Ceil(currentLSB_mantissa) * math.Pow(10, math.Floor((math.Log10(currentLSB))))
-	currentLSB = currentLSB_approx / 1000000 //transform back to Ampere
-
-	calibrationValue := uint16((0.00512) / (currentLSB * ina226.rShunt))
-
-	var buf []byte
-	buf = append(buf, CALIBRATION_REG)
-	buf = append(buf, wordToByteArray(calibrationValue)...)
-
-	_, err := ina226.device.Write(buf)
+	return ina226.SetMaxCurrentShunt(iMaxValue, rShuntValue, true)
+}
+
+// SetMaxCurrentShunt programs the CALIBRATION register from the expected maximum
+// current and the shunt resistor value, mirroring the Arduino library's API.
+// With normalize=true the current_LSB is rounded up to the mantissa-ceiling value
+// as Calibrate does; with normalize=false the raw current_LSB is used, trading the
+// "round number" LSB for finer resolution. Returns ErrCalibrationOutOfRange if the
+// pair cannot be represented in the 15-bit CALIBRATION register.
+func (ina226 *Ina226) SetMaxCurrentShunt(maxCurrent, shunt float64, normalize bool) error {
+	currentLSB, calibrationValue, err := ina226.calibrationFor(maxCurrent, shunt, normalize)
 	if err != nil {
 		return err
 	}
+
+	err = ina226.bus.WriteRegister(CALIBRATION_REG, calibrationValue)
+	if err != nil {
+		return err
+	}
+
+	ina226.rShunt = shunt
+	ina226.iMax = maxCurrent
+	ina226.currentLSB = currentLSB
 	return nil
+}
 
+// MaxCurrent returns the maximum current last programmed via Calibrate or
+// SetMaxCurrentShunt.
+func (ina226 *Ina226) MaxCurrent() float64 {
+	return ina226.iMax
+}
+
+// Shunt returns the shunt resistor value, in Ohm, last programmed via Calibrate
+// or SetMaxCurrentShunt.
+func (ina226 *Ina226) Shunt() float64 {
+	return ina226.rShunt
+}
+
+// CurrentLSB returns the current_LSB, in Ampere, that was actually programmed
+// into the CALIBRATION register by the last call to Calibrate or
+// SetMaxCurrentShunt.
+func (ina226 *Ina226) CurrentLSB() float64 {
+	return ina226.currentLSB
 }
 
 func (ina226 *Ina226) Reset() error {
 	// reset bit ins in the configure register
-	var buf []byte
-	buf = append(buf, CONFIG_REG)
-	buf = append(buf, wordToByteArray(INA226_RST)...)
-	_, err := ina226.device.Write(buf)
-	if err != nil {
-		return err
-	}
-	return nil
+	return ina226.bus.WriteRegister(CONFIG_REG, INA226_RST)
 }
 
 func (ina226 *Ina226) readRegister16(reg byte) (uint16, error) {
-	// send request to register
-	_, err := ina226.device.Write([]byte{reg})
-	if err != nil {
-		return 0, err
-	}
-	//read the 16 bit register content
-	buf := make([]byte, 2)
-	_, err = ina226.device.Read(buf)
-	if err != nil {
-		return 0, err
-	}
-
-	value := uint16(buf[0])<<8 | uint16(buf[1])
-	return value, nil
+	return ina226.bus.ReadRegister(reg)
 }
 
 func (ina226 *Ina226) readConfigurationRegister() (uint16, error) {
@@ -264,3 +340,182 @@ func (ina226 *Ina226) ReadShuntCurrent() (float64, error) {
 
 	return float64(currentRaw) * currentResolution, nil
 }
+
+// ReadPower reads the POWER register and returns the bus power in Watt, computed
+// as raw * Power_LSB where Power_LSB = 25 * Current_LSB. The device must have been
+// calibrated with Calibrate beforehand.
+func (ina226 *Ina226) ReadPower() (float64, error) {
+	if ina226.currentLSB <= 0.0 {
+		return 0, errors.New("device is not calibrated, call Calibrate first")
+	}
+
+	powerRaw, err := ina226.readRegister16(POWER_REG)
+	if err != nil {
+		return 0, err
+	}
+
+	return float64(powerRaw) * 25 * ina226.currentLSB, nil
+}
+
+// ReadBusVoltageMicroVolts reads the BUS_VOLTAGE register and returns the bus
+// voltage in microvolt (1.25 mV per LSB), as an integer to avoid float rounding.
+func (ina226 *Ina226) ReadBusVoltageMicroVolts() (int64, error) {
+	voltage, err := ina226.readRegister16(BUSVOLTAGE_REG)
+	if err != nil {
+		return 0, err
+	}
+	return int64(voltage) * 1250, nil
+}
+
+// ReadShuntVoltageMicroVolts reads the SHUNT_VOLTAGE register and returns the
+// shunt voltage in microvolt (2.5 µV per LSB), as an integer to avoid float
+// rounding.
+func (ina226 *Ina226) ReadShuntVoltageMicroVolts() (int64, error) {
+	voltage, err := ina226.readRegister16(SHUNTVOLTAGE_REG)
+	if err != nil {
+		return 0, err
+	}
+	return int64(int16(voltage)) * 25 / 10, nil
+}
+
+// Measurement is a snapshot of a single INA226 reading, taken with ReadAll.
+type Measurement struct {
+	BusVoltage   float64
+	ShuntVoltage float64
+	Current      float64
+	Power        float64
+}
+
+// ReadAll returns bus voltage, shunt voltage, current and power in a single
+// call. It calls ReadBusVoltage, ReadShuntVoltage, ReadShuntCurrent and
+// ReadPower in turn, so it makes the same register reads as those four calls
+// (five in all, counting the CALIBRATION read of ReadShuntCurrent), and the
+// values come from separate reads rather than one atomic snapshot.
+func (ina226 *Ina226) ReadAll() (Measurement, error) {
+	busVoltage, err := ina226.ReadBusVoltage()
+	if err != nil {
+		return Measurement{}, err
+	}
+
+	shuntVoltage, err := ina226.ReadShuntVoltage()
+	if err != nil {
+		return Measurement{}, err
+	}
+
+	current, err := ina226.ReadShuntCurrent()
+	if err != nil {
+		return Measurement{}, err
+	}
+
+	power, err := ina226.ReadPower()
+	if err != nil {
+		return Measurement{}, err
+	}
+
+	return Measurement{
+		BusVoltage:   busVoltage,
+		ShuntVoltage: shuntVoltage,
+		Current:      current,
+		Power:        power,
+	}, nil
+}
+
+func (ina226 *Ina226) writeAlertLimit(raw uint16) error {
+	return ina226.bus.WriteRegister(ALERTLIMIT_REG, raw)
+}
+
+// SetAlertFunction programs the MASK/ENABLE register, selecting which condition
+// drives the ALERT pin. mode must be exactly one of INA226_SHUNT_OVER_VOLTAGE,
+// INA226_SHUNT_UNDER_VOLTAGE, INA226_BUS_OVER_VOLTAGE, INA226_BUS_UNDER_VOLTAGE,
+// INA226_POWER_OVER_LIMIT or INA226_CONVERSION_READY. opts can additionally OR in
+// INA226_ALERT_POLARITY and/or INA226_ALERT_LATCH_ENABLE.
+// Call SetAlertLimitVoltage, SetAlertLimitCurrent or SetAlertLimitPower afterwards
+// to program the matching threshold into ALERT_LIMIT.
+func (ina226 *Ina226) SetAlertFunction(mode uint16, opts ...uint16) error {
+	// mode must be a single bit (not zero, not out-of-mask, not several bits
+	// OR'd together), since SetAlertLimit* dispatch on the exact value of
+	// ina226.alertMode.
+	if mode&alertFunctionMask == 0 || mode&^alertFunctionMask != 0 || mode&(mode-1) != 0 {
+		return errors.New(fmt.Sprintf("mode: 0x%04X is not a valid alert function", mode))
+	}
+
+	maskEnable := mode
+	for _, opt := range opts {
+		maskEnable |= opt
+	}
+
+	err := ina226.bus.WriteRegister(MASKENABLE_REG, maskEnable)
+	if err != nil {
+		return err
+	}
+
+	ina226.alertMode = mode
+	return nil
+}
+
+// SetAlertLimitVoltage programs ALERT_LIMIT as a bus or shunt voltage threshold,
+// expressed in Volt. The scaling depends on the alert function last selected with
+// SetAlertFunction: 2.5 µV/bit for the shunt voltage functions, 1.25 mV/bit for the
+// bus voltage functions.
+func (ina226 *Ina226) SetAlertLimitVoltage(v float64) error {
+	var raw int16
+
+	switch ina226.alertMode {
+	case INA226_SHUNT_OVER_VOLTAGE, INA226_SHUNT_UNDER_VOLTAGE:
+		raw = int16(v / 0.0000025)
+	case INA226_BUS_OVER_VOLTAGE, INA226_BUS_UNDER_VOLTAGE:
+		raw = int16(v / 0.00125)
+	default:
+		return errors.New(fmt.Sprintf("alert function 0x%04X does not take a voltage limit, call SetAlertFunction first", ina226.alertMode))
+	}
+
+	return ina226.writeAlertLimit(uint16(raw))
+}
+
+// SetAlertLimitCurrent programs ALERT_LIMIT as a shunt current threshold, expressed
+// in Ampere. It requires the alert function to be one of the shunt voltage functions
+// and the device to have been calibrated with Calibrate, so the current can be
+// translated into the equivalent shunt voltage.
+func (ina226 *Ina226) SetAlertLimitCurrent(a float64) error {
+	if ina226.alertMode != INA226_SHUNT_OVER_VOLTAGE && ina226.alertMode != INA226_SHUNT_UNDER_VOLTAGE {
+		return errors.New(fmt.Sprintf("alert function 0x%04X does not take a current limit, call SetAlertFunction(INA226_SHUNT_OVER/UNDER_VOLTAGE) first", ina226.alertMode))
+	}
+	if ina226.rShunt <= 0.0 {
+		return errors.New(fmt.Sprintf("rShunt value: %f is not correct. Must be greater than 0", ina226.rShunt))
+	}
+
+	return ina226.SetAlertLimitVoltage(a * ina226.rShunt)
+}
+
+// SetAlertLimitPower programs ALERT_LIMIT as a power threshold, expressed in Watt.
+// It requires the alert function to be INA226_POWER_OVER_LIMIT and the device to
+// have been calibrated with Calibrate, per the datasheet's Power_LSB = 25 * Current_LSB.
+func (ina226 *Ina226) SetAlertLimitPower(w float64) error {
+	if ina226.alertMode != INA226_POWER_OVER_LIMIT {
+		return errors.New(fmt.Sprintf("alert function 0x%04X does not take a power limit, call SetAlertFunction(INA226_POWER_OVER_LIMIT) first", ina226.alertMode))
+	}
+	if ina226.currentLSB <= 0.0 {
+		return errors.New("device is not calibrated, call Calibrate first")
+	}
+
+	powerLSB := 25 * ina226.currentLSB
+	raw := uint16(w / powerLSB)
+	return ina226.writeAlertLimit(raw)
+}
+
+// ReadAlertFlags reads the MASK/ENABLE register and decodes its read-only status
+// bits: mathOverflow (OVF, the shunt current/power calculation overflowed),
+// conversionReady (CVRF, a conversion has completed) and alertFunc (AFF, the
+// configured alert function has tripped). Per the datasheet, reading MASK/ENABLE
+// clears both CVRF and AFF, so each call consumes the flags it returns.
+func (ina226 *Ina226) ReadAlertFlags() (mathOverflow, conversionReady, alertFunc bool, err error) {
+	maskEnable, err := ina226.readRegister16(MASKENABLE_REG)
+	if err != nil {
+		return false, false, false, err
+	}
+
+	mathOverflow = maskEnable&INA226_MATH_OVERFLOW_FLAG != 0
+	conversionReady = maskEnable&INA226_CONVERSION_READY_FLAG != 0
+	alertFunc = maskEnable&INA226_ALERT_FUNCTION_FLAG != 0
+	return mathOverflow, conversionReady, alertFunc, nil
+}
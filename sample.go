@@ -0,0 +1,195 @@
+package ina226
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// AlertPin is the hardware-abstraction the Sample loop waits on when
+// SampleOptions.AlertPin is supplied, instead of polling the Conversion
+// Ready flag. It models a GPIO pin wired to the INA226's ALERT output.
+type AlertPin interface {
+	// WaitForEdge blocks until the pin sees an edge (as configured by the
+	// caller's wiring/polarity) or ctx is cancelled, in which case it must
+	// return ctx.Err().
+	WaitForEdge(ctx context.Context) error
+}
+
+// Sample is one completed conversion, as emitted on the channel returned by
+// Sample, or the final value on that channel if sampling stopped because of
+// an error. Err is nil on every sample but the last, and only non-nil there
+// if the stream stopped because of a failure rather than ctx being done: a
+// clean, ctx-cancelled shutdown just closes the channel with no trailing
+// error Sample.
+type Sample struct {
+	Time    time.Time
+	BusV    float64
+	ShuntV  float64
+	Current float64
+	Power   float64
+	Err     error
+}
+
+// SampleOptions configures the Sample streaming loop.
+type SampleOptions struct {
+	// Config is passed to Configure before sampling starts. It must select
+	// one of the *_CONT modes so the device keeps converting on its own.
+	Config []uint16
+
+	// AlertPin, if set, is waited on for a Conversion Ready edge instead of
+	// polling the CVRF bit in MASK/ENABLE.
+	AlertPin AlertPin
+
+	// BufferSize sizes the returned channel. Zero means unbuffered: the
+	// device keeps converting, but the loop doesn't wait for or read the
+	// next conversion until the previous Sample has been received.
+	BufferSize int
+}
+
+// minPollInterval floors the poll period Sample derives from
+// ComputeConversionInterval, so a very short configured conversion time
+// doesn't turn CVRF polling into a busy loop.
+const minPollInterval = 50 * time.Microsecond
+
+// Sample programs the device per opts.Config, arms a Conversion Ready alert
+// through MASK/ENABLE, and streams one Sample per completed conversion on
+// the returned channel until ctx is cancelled, at which point the channel is
+// closed. It uses the Conversion Ready flag (or, if SampleOptions.AlertPin is
+// set, that pin's edge) rather than a fixed sleep, so samples line up with
+// the configured averaging/conversion time instead of a guessed polling
+// period. The device must already be calibrated with Calibrate or
+// SetMaxCurrentShunt, since each Sample includes Power.
+func (ina226 *Ina226) Sample(ctx context.Context, opts SampleOptions) (<-chan Sample, error) {
+	if ina226.currentLSB <= 0.0 {
+		return nil, errors.New("device is not calibrated, call Calibrate first")
+	}
+
+	if err := ina226.Configure(opts.Config...); err != nil {
+		return nil, err
+	}
+
+	// Transparent mode (no INA226_ALERT_LATCH_ENABLE): the ALERT pin is
+	// deasserted as soon as MASK/ENABLE is read, which is what lets it fire
+	// again on the next conversion. waitForConversion always reads
+	// MASK/ENABLE after an edge (or while polling) to clear CVRF.
+	if err := ina226.SetAlertFunction(INA226_CONVERSION_READY); err != nil {
+		return nil, err
+	}
+
+	var configuration uint16
+	for _, conf := range opts.Config {
+		configuration |= conf
+	}
+
+	// Poll at a fraction of the expected conversion interval: polling no
+	// faster than the interval itself risks several conversions completing
+	// between polls and collapsing into a single read, silently dropping
+	// windows instead of emitting one Sample per window.
+	pollInterval := ComputeConversionInterval(configuration) / 4
+	if pollInterval < minPollInterval {
+		pollInterval = minPollInterval
+	}
+
+	samples := make(chan Sample, opts.BufferSize)
+
+	go func() {
+		defer close(samples)
+
+		for {
+			if err := ina226.waitForConversion(ctx, opts.AlertPin, pollInterval); err != nil {
+				if ctx.Err() == nil {
+					sendSampleErr(ctx, samples, err)
+				}
+				return
+			}
+
+			m, err := ina226.ReadAll()
+			if err != nil {
+				sendSampleErr(ctx, samples, err)
+				return
+			}
+
+			select {
+			case samples <- Sample{Time: time.Now(), BusV: m.BusVoltage, ShuntV: m.ShuntVoltage, Current: m.Current, Power: m.Power}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return samples, nil
+}
+
+// sendSampleErr makes a best-effort attempt to deliver a terminal error
+// Sample before the channel is closed, without blocking past ctx.
+func sendSampleErr(ctx context.Context, samples chan<- Sample, err error) {
+	select {
+	case samples <- Sample{Time: time.Now(), Err: err}:
+	case <-ctx.Done():
+	}
+}
+
+// waitForConversion blocks until the currently configured conversion
+// completes: on the supplied pin's edge if one is given, otherwise by
+// polling CVRF in MASK/ENABLE every pollInterval. Either way it reads
+// MASK/ENABLE once the conversion is done, which per the datasheet clears
+// CVRF and deasserts the ALERT pin so the next conversion can trigger it
+// again.
+func (ina226 *Ina226) waitForConversion(ctx context.Context, pin AlertPin, pollInterval time.Duration) error {
+	if pin != nil {
+		if err := pin.WaitForEdge(ctx); err != nil {
+			return err
+		}
+		_, _, _, err := ina226.ReadAlertFlags()
+		return err
+	}
+
+	for {
+		_, conversionReady, _, err := ina226.ReadAlertFlags()
+		if err != nil {
+			return err
+		}
+		if conversionReady {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// conversionTimesUS are the VSHCT/VBUSCT conversion times, in microsecond,
+// indexed by the 3-bit field value (see the *_CONV_TIME_* constants).
+var conversionTimesUS = [8]int{140, 204, 332, 588, 1100, 2116, 4156, 8244}
+
+// averagingCounts are the AVG multipliers, indexed by the 3-bit field value
+// (see the AVERAGES_* constants).
+var averagingCounts = [8]int{1, 4, 16, 64, 128, 256, 512, 1024}
+
+// ComputeConversionInterval decodes the MODE/AVG/VBUSCT/VSHCT bits of a
+// configuration word, as would be passed to Configure, into the actual
+// expected time between completed conversions. This lets callers size
+// Sample's channel buffer, or pick a CVRF poll period, that matches the
+// averaging/conversion time they configured instead of guessing.
+func ComputeConversionInterval(cfg uint16) time.Duration {
+	mode := cfg & 0x07
+	vshct := (cfg >> 3) & 0x07
+	vbusct := (cfg >> 6) & 0x07
+	avg := (cfg >> 9) & 0x07
+
+	var perSampleUS int
+	switch mode {
+	case INA226_MODE_SHUNT_TRIG, INA226_MODE_SHUNT_CONT:
+		perSampleUS = conversionTimesUS[vshct]
+	case INA226_MODE_BUS_TRIG, INA226_MODE_BUS_CONT:
+		perSampleUS = conversionTimesUS[vbusct]
+	case INA226_MODE_SHUNT_BUS_TRIG, INA226_MODE_SHUNT_BUS_CONT:
+		perSampleUS = conversionTimesUS[vshct] + conversionTimesUS[vbusct]
+	}
+
+	return time.Duration(perSampleUS*averagingCounts[avg]) * time.Microsecond
+}